@@ -1,41 +1,198 @@
 package logic
 
 import (
-	"leader_board/leader_board/model"
-	"sort"
 	"sync"
+	"time"
+
+	"leader_board/leader_board/logic/persistence"
+	"leader_board/leader_board/logic/zset"
+	"leader_board/leader_board/model"
 )
 
 type LeaderboardService struct {
 	mu      sync.RWMutex
 	players map[string]*model.PlayerEntry
-	ranking []*model.PlayerEntry
+	ranking *zset.Skiplist
+	cmp     model.Comparator
+
+	// store 为空表示不开启持久化，行为与此前完全一致。
+	store persistence.Store
+	// storeMu 串行化 writeLoop 的 AppendWAL 与 doSnapshot 的"拷贝状态 -> 落盘 -> 清空 WAL"，
+	// 否则快照已经拷贝完状态、但还没来得及 TruncateWAL 时，若恰好有一条新的 WAL 写入插进来，
+	// 这条写入会被随后的 TruncateWAL 连带清空，而它又没被包含在这份快照里，数据就丢了。
+	storeMu      sync.Mutex
+	writeCh      chan *model.PlayerEntry
+	snapshotStop chan struct{}
+	wg           sync.WaitGroup
 }
 
-// NewLeaderboardService 创建排行榜服务
+// NewLeaderboardService 创建排行榜服务，排序沿用一直以来的行为：
+// 分数越高排名越靠前，分数相同则时间戳越早排名越靠前。
 func NewLeaderboardService() *LeaderboardService {
+	return newLeaderboardService(model.ScoreDescTimeAsc)
+}
+
+// NewLeaderboardServiceWithComparator 创建排行榜服务，按 cmp 决定排序，
+// 用于需要自定义排序（如多维度分数）的场景。
+func NewLeaderboardServiceWithComparator(cmp model.Comparator) *LeaderboardService {
+	return newLeaderboardService(cmp)
+}
+
+func newLeaderboardService(cmp model.Comparator) *LeaderboardService {
 	return &LeaderboardService{
 		players: make(map[string]*model.PlayerEntry),
-		ranking: make([]*model.PlayerEntry, 0),
+		ranking: zset.NewSkiplist(cmp),
+		cmp:     cmp,
+	}
+}
+
+// NewLeaderboardServiceWithStore 创建带持久化能力的排行榜服务：先从最近一次快照恢复状态，
+// 再重放快照之后的 WAL，然后启动异步写入和按 snapshotInterval 定时打快照的后台协程。
+// 排序固定为 ScoreDescTimeAsc；NewLeaderboardServiceWithComparator 创建的服务不带持久化，
+// 两者不能组合使用。
+func NewLeaderboardServiceWithStore(store persistence.Store, snapshotInterval time.Duration) (*LeaderboardService, error) {
+	lb := newLeaderboardService(model.ScoreDescTimeAsc)
+	lb.store = store
+	lb.writeCh = make(chan *model.PlayerEntry, 1024)
+	lb.snapshotStop = make(chan struct{})
+	if err := lb.restore(); err != nil {
+		return nil, err
+	}
+	lb.wg.Add(2)
+	go lb.writeLoop()
+	go lb.snapshotLoop(snapshotInterval)
+	return lb, nil
+}
+
+// restore 依次重放最近一份快照和其后的 WAL，重建内存状态。
+func (lb *LeaderboardService) restore() error {
+	snapshot, err := lb.store.LoadSnapshot()
+	if err != nil {
+		return err
+	}
+	for _, entry := range snapshot {
+		lb.players[entry.PlayerID] = entry
+		lb.ranking.Insert(entry)
+	}
+
+	wal, err := lb.store.LoadWAL()
+	if err != nil {
+		return err
+	}
+	for _, entry := range wal {
+		lb.applyScore(entry.PlayerID, entry.Score, entry.Timestamp, entry.Extra)
+	}
+	return nil
+}
+
+// writeLoop 消费异步写入队列，把每次分数变更追加进 WAL。
+func (lb *LeaderboardService) writeLoop() {
+	defer lb.wg.Done()
+	for entry := range lb.writeCh {
+		lb.storeMu.Lock()
+		lb.store.AppendWAL(entry)
+		lb.storeMu.Unlock()
+	}
+}
+
+// snapshotLoop 按固定周期把当前全量状态落盘，并清空此前的 WAL。
+func (lb *LeaderboardService) snapshotLoop(interval time.Duration) {
+	defer lb.wg.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			lb.doSnapshot()
+		case <-lb.snapshotStop:
+			return
+		}
+	}
+}
+
+func (lb *LeaderboardService) doSnapshot() {
+	// 持有 storeMu 贯穿整个"拷贝状态 -> 落盘 -> 清空 WAL"过程，使其与 writeLoop 的
+	// AppendWAL 互斥：writeLoop 里排队的写入要么在这之前完成（已经反映在下面拷贝的
+	// 状态里，即使快照之后仍重放一次也是幂等的），要么被 storeMu 挡到 TruncateWAL
+	// 之后才写入 WAL，不会出现"写入发生在拷贝之后、却被截断清空"的数据丢失窗口。
+	lb.storeMu.Lock()
+	defer lb.storeMu.Unlock()
+
+	lb.mu.RLock()
+	entries := make([]*model.PlayerEntry, 0, len(lb.players))
+	for _, entry := range lb.players {
+		snap := *entry
+		entries = append(entries, &snap)
+	}
+	lb.mu.RUnlock()
+
+	if err := lb.store.SaveSnapshot(entries); err != nil {
+		return
+	}
+	lb.store.TruncateWAL()
+}
+
+// Close 停止后台协程、落一份最终快照并关闭底层存储；未开启持久化时是空操作。
+func (lb *LeaderboardService) Close() error {
+	if lb.store == nil {
+		return nil
 	}
+	close(lb.snapshotStop)
+	close(lb.writeCh)
+	lb.wg.Wait()
+	lb.doSnapshot()
+	return lb.store.Close()
 }
 
 // 更新玩家分数
 func (lb *LeaderboardService) UpdateScore(playerId string, score int, timestamp int64) {
+	lb.updateScore(playerId, score, timestamp, nil)
+}
+
+// UpdateScoreWithExtra 在更新分数/时间戳的同时设置玩家的多维度分数（Extra），
+// 供按 Lexicographic 等自定义 Comparator 排序的排行榜使用。
+func (lb *LeaderboardService) UpdateScoreWithExtra(playerId string, score int, timestamp int64, extra map[string]float64) {
+	lb.updateScore(playerId, score, timestamp, extra)
+}
+
+func (lb *LeaderboardService) updateScore(playerId string, score int, timestamp int64, extra map[string]float64) {
 	lb.mu.Lock()
-	defer lb.mu.Unlock()
+	lb.applyScore(playerId, score, timestamp, extra)
+	entry := lb.players[playerId]
+	lb.mu.Unlock()
 
+	if lb.store != nil {
+		snap := *entry
+		lb.writeCh <- &snap
+	}
+}
+
+// applyScore 是 updateScore 的核心逻辑，供正常写入和 WAL 重放共用；调用方需持有 lb.mu。
+// extra 为 nil 时保留该玩家已有的 Extra。
+func (lb *LeaderboardService) applyScore(playerId string, score int, timestamp int64, extra map[string]float64) {
 	entry, exists := lb.players[playerId]
 	if exists {
 		// 先移除旧的
-		lb.removeFromRanking(entry)
+		lb.ranking.Delete(entry)
 		entry.Score = score
 		entry.Timestamp = timestamp
+		if extra != nil {
+			entry.Extra = extra
+		}
 	} else {
-		entry = &model.PlayerEntry{PlayerID: playerId, Score: score, Timestamp: timestamp}
+		entry = &model.PlayerEntry{PlayerID: playerId, Score: score, Timestamp: timestamp, Extra: extra}
 		lb.players[playerId] = entry
 	}
-	lb.insertToRanking(entry)
+	lb.ranking.Insert(entry)
+}
+
+// CountAbove 返回排在 entry 之前的玩家数，打破同组并列的规则与 GetPlayerRank
+// 给玩家定位时完全一致（先比较 Comparator，再以 PlayerID 兜底），
+// 供 ShardedLeaderboardService 合并各分片本地名次为全局名次时使用。
+func (lb *LeaderboardService) CountAbove(entry *model.PlayerEntry) int {
+	lb.mu.RLock()
+	defer lb.mu.RUnlock()
+	return lb.ranking.CountAbove(entry)
 }
 
 // 获取玩家当前排名
@@ -46,12 +203,16 @@ func (lb *LeaderboardService) GetPlayerRank(playerId string) *model.RankInfo {
 	if !exists {
 		return nil
 	}
-	rank := lb.findRank(entry)
+	rank, ok := lb.ranking.GetRank(entry)
+	if !ok {
+		return nil
+	}
 	return &model.RankInfo{
 		PlayerID:  entry.PlayerID,
 		Score:     entry.Score,
 		Rank:      rank + 1,
 		Timestamp: entry.Timestamp,
+		Extra:     entry.Extra,
 	}
 }
 
@@ -59,14 +220,16 @@ func (lb *LeaderboardService) GetPlayerRank(playerId string) *model.RankInfo {
 func (lb *LeaderboardService) GetTopN(n int) []model.RankInfo {
 	lb.mu.RLock()
 	defer lb.mu.RUnlock()
-	res := make([]model.RankInfo, 0, n)
-	for i := 0; i < n && i < len(lb.ranking); i++ {
-		entry := lb.ranking[i]
+	nodes := lb.ranking.GetRange(0, n-1)
+	res := make([]model.RankInfo, 0, len(nodes))
+	for i, node := range nodes {
+		entry := node.Entry()
 		res = append(res, model.RankInfo{
 			PlayerID:  entry.PlayerID,
 			Score:     entry.Score,
 			Rank:      i + 1,
 			Timestamp: entry.Timestamp,
+			Extra:     entry.Extra,
 		})
 	}
 	return res
@@ -80,102 +243,53 @@ func (lb *LeaderboardService) GetPlayerRankRange(playerId string, rng int) []mod
 	if !exists {
 		return nil
 	}
-	rank := lb.findRank(entry)
+	rank, ok := lb.ranking.GetRank(entry)
+	if !ok {
+		return nil
+	}
 	start := rank - rng
 	if start < 0 {
 		start = 0
 	}
 	end := rank + rng
-	if end >= len(lb.ranking) {
-		end = len(lb.ranking) - 1
-	}
-	res := make([]model.RankInfo, 0, end-start+1)
-	for i := start; i <= end; i++ {
-		e := lb.ranking[i]
+	nodes := lb.ranking.GetRange(start, end)
+	res := make([]model.RankInfo, 0, len(nodes))
+	for i, node := range nodes {
+		e := node.Entry()
 		res = append(res, model.RankInfo{
 			PlayerID:  e.PlayerID,
 			Score:     e.Score,
-			Rank:      i + 1,
+			Rank:      start + i + 1,
 			Timestamp: e.Timestamp,
+			Extra:     e.Extra,
 		})
 	}
 	return res
 }
 
-// 内部方法：插入到排行榜
-func (lb *LeaderboardService) insertToRanking(entry *model.PlayerEntry) {
-	idx := sort.Search(len(lb.ranking), func(i int) bool {
-		if lb.ranking[i].Score < entry.Score {
-			return true
-		}
-		if lb.ranking[i].Score == entry.Score {
-			return lb.ranking[i].Timestamp > entry.Timestamp
-		}
-		return false
-	})
-	lb.ranking = append(lb.ranking, nil)
-	copy(lb.ranking[idx+1:], lb.ranking[idx:])
-	lb.ranking[idx] = entry
-}
-
-// 内部方法：移除旧排名
-func (lb *LeaderboardService) removeFromRanking(entry *model.PlayerEntry) {
-	for i, e := range lb.ranking {
-		if e == entry {
-			lb.ranking = append(lb.ranking[:i], lb.ranking[i+1:]...)
-			return
-		}
-	}
-}
-
-// 内部方法：查找排名
-func (lb *LeaderboardService) findRank(entry *model.PlayerEntry) int {
-	for i, e := range lb.ranking {
-		if e == entry {
-			return i
-		}
-	}
-	return -1
-}
-
 // ------------------------------------以下密集排名的方法------------------------------------
-
-func (lb *LeaderboardService) findDenseRank(entry *model.PlayerEntry) int {
-	rank := 1
-	prevScore := -1
-	for i, e := range lb.ranking {
-		if i == 0 || e.Score != prevScore {
-			if i != 0 {
-				rank++
-			}
-			prevScore = e.Score
-		}
-		if e == entry {
-			return rank
-		}
-	}
-	return -1
-}
+// 密集排名按 Comparator 判定的同组（Comparator(a, b) == 0）分配同一个名次，
+// 而不是原先硬编码比较 Score 字段。
 
 func (lb *LeaderboardService) GetDenseTopN(n int) []model.RankInfo {
 	lb.mu.RLock()
 	defer lb.mu.RUnlock()
-	res := make([]model.RankInfo, 0, n)
+	nodes := lb.ranking.GetRange(0, n-1)
+	res := make([]model.RankInfo, 0, len(nodes))
 	rank := 1
-	prevScore := -1
-	for i := 0; i < n && i < len(lb.ranking); i++ {
-		entry := lb.ranking[i]
-		if i == 0 || entry.Score != prevScore {
-			if i != 0 {
-				rank++
-			}
-			prevScore = entry.Score
+	var prev *model.PlayerEntry
+	for i, node := range nodes {
+		entry := node.Entry()
+		if i != 0 && lb.cmp(prev, entry) != 0 {
+			rank++
 		}
+		prev = entry
 		res = append(res, model.RankInfo{
 			PlayerID:  entry.PlayerID,
 			Score:     entry.Score,
 			Rank:      rank,
 			Timestamp: entry.Timestamp,
+			Extra:     entry.Extra,
 		})
 	}
 	return res
@@ -189,23 +303,22 @@ func (lb *LeaderboardService) GetPlayerDenseRankRange(playerId string, rng int)
 		return nil
 	}
 
-	denseRanks := make([]int, len(lb.ranking))
+	nodes := lb.ranking.GetRange(0, lb.ranking.Len()-1)
+	denseRanks := make([]int, len(nodes))
 	rank := 1
-	prevScore := -1
-	for i, e := range lb.ranking {
-		if i == 0 || e.Score != prevScore {
-			if i != 0 {
-				rank++
-			}
-			prevScore = e.Score
+	var prev *model.PlayerEntry
+	for i, node := range nodes {
+		e := node.Entry()
+		if i != 0 && lb.cmp(prev, e) != 0 {
+			rank++
 		}
+		prev = e
 		denseRanks[i] = rank
 	}
-	//idx := 0 // 以玩家为中心前后rng个 才会使用这个
+
 	playerDenseRank := 0
-	for i, e := range lb.ranking {
-		if e == entry {
-			//idx = i
+	for i, node := range nodes {
+		if node.Entry() == entry {
 			playerDenseRank = denseRanks[i]
 			break
 		}
@@ -214,12 +327,13 @@ func (lb *LeaderboardService) GetPlayerDenseRankRange(playerId string, rng int)
 	res := make([]model.RankInfo, 0, 2*rng+1)
 	for i, r := range denseRanks {
 		if r >= playerDenseRank-rng && r <= playerDenseRank+rng {
-			e := lb.ranking[i]
+			e := nodes[i].Entry()
 			res = append(res, model.RankInfo{
 				PlayerID:  e.PlayerID,
 				Score:     e.Score,
 				Rank:      r,
 				Timestamp: e.Timestamp,
+				Extra:     e.Extra,
 			})
 		}
 	}
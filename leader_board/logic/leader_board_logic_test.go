@@ -0,0 +1,97 @@
+package logic
+
+import (
+	"testing"
+	"time"
+
+	"leader_board/leader_board/logic/persistence"
+)
+
+// TestLeaderboardServiceRestoreAfterClose 验证：用 FileStore 写入若干分数变更后
+// Close 服务（模拟进程退出），再用同一个目录重新打开一个新的服务实例，能够
+// 恢复出与关闭前完全一致的排名，证明快照 + WAL 确实能在重启后找回数据。
+func TestLeaderboardServiceRestoreAfterClose(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := persistence.NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileStore: %v", err)
+	}
+	lb, err := NewLeaderboardServiceWithStore(store, time.Hour)
+	if err != nil {
+		t.Fatalf("NewLeaderboardServiceWithStore: %v", err)
+	}
+	lb.UpdateScore("alice", 100, 1)
+	lb.UpdateScore("bob", 200, 2)
+	lb.UpdateScore("carol", 150, 3)
+	if err := lb.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	reopenedStore, err := persistence.NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileStore (reopen): %v", err)
+	}
+	reopened, err := NewLeaderboardServiceWithStore(reopenedStore, time.Hour)
+	if err != nil {
+		t.Fatalf("NewLeaderboardServiceWithStore (reopen): %v", err)
+	}
+	defer reopened.Close()
+
+	wantRanks := map[string]int{"bob": 1, "carol": 2, "alice": 3}
+	for playerId, wantRank := range wantRanks {
+		info := reopened.GetPlayerRank(playerId)
+		if info == nil {
+			t.Fatalf("GetPlayerRank(%q) = nil after reopen, want rank %d", playerId, wantRank)
+		}
+		if info.Rank != wantRank {
+			t.Errorf("GetPlayerRank(%q).Rank = %d, want %d", playerId, info.Rank, wantRank)
+		}
+	}
+}
+
+// TestLeaderboardServiceRestoreFromWALTail 验证：即使从未触发过快照（快照周期设得
+// 很长），重新打开服务仍能通过重放 WAL 尾部恢复出关闭前提交的全部分数变更。
+func TestLeaderboardServiceRestoreFromWALTail(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := persistence.NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileStore: %v", err)
+	}
+	lb, err := NewLeaderboardServiceWithStore(store, time.Hour)
+	if err != nil {
+		t.Fatalf("NewLeaderboardServiceWithStore: %v", err)
+	}
+	lb.UpdateScore("dave", 50, 1)
+	lb.UpdateScore("erin", 75, 2)
+	// 直接关闭底层存储的写协程而不经过 Close（Close 会强制打一次快照），
+	// 模拟进程在两次快照之间被杀掉、只有 WAL 落了盘的场景。
+	close(lb.writeCh)
+	close(lb.snapshotStop)
+	lb.wg.Wait()
+	if err := lb.store.Close(); err != nil {
+		t.Fatalf("store.Close: %v", err)
+	}
+
+	reopenedStore, err := persistence.NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileStore (reopen): %v", err)
+	}
+	reopened, err := NewLeaderboardServiceWithStore(reopenedStore, time.Hour)
+	if err != nil {
+		t.Fatalf("NewLeaderboardServiceWithStore (reopen): %v", err)
+	}
+	defer reopened.Close()
+
+	wantRanks := map[string]int{"erin": 1, "dave": 2}
+	for playerId, wantRank := range wantRanks {
+		info := reopened.GetPlayerRank(playerId)
+		if info == nil {
+			t.Fatalf("GetPlayerRank(%q) = nil after reopen, want rank %d", playerId, wantRank)
+		}
+		if info.Rank != wantRank {
+			t.Errorf("GetPlayerRank(%q).Rank = %d, want %d", playerId, info.Rank, wantRank)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package persistence
+
+import (
+	"bufio"
+	"encoding/gob"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"leader_board/leader_board/model"
+)
+
+// FileStore 是基于本地文件的 Store 实现：快照用 gob 整体编码，WAL 用 JSON 按行追加，
+// 方便在恢复时顺序 tail 读取。
+type FileStore struct {
+	mu           sync.Mutex
+	snapshotPath string
+	walPath      string
+	walFile      *os.File
+}
+
+// NewFileStore 在 dir 目录下创建/打开快照文件与 WAL 文件。
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	walPath := filepath.Join(dir, "leaderboard.wal")
+	walFile, err := os.OpenFile(walPath, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileStore{
+		snapshotPath: filepath.Join(dir, "leaderboard.snapshot"),
+		walPath:      walPath,
+		walFile:      walFile,
+	}, nil
+}
+
+// SaveSnapshot 原子地写入一份新快照：先写临时文件再 rename，避免写一半被读到。
+func (fs *FileStore) SaveSnapshot(entries []*model.PlayerEntry) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	tmpPath := fs.snapshotPath + ".tmp"
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	if err := gob.NewEncoder(f).Encode(entries); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, fs.snapshotPath)
+}
+
+// LoadSnapshot 加载最近一份快照；文件不存在时返回 (nil, nil)。
+func (fs *FileStore) LoadSnapshot() ([]*model.PlayerEntry, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	f, err := os.Open(fs.snapshotPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []*model.PlayerEntry
+	if err := gob.NewDecoder(f).Decode(&entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// AppendWAL 以 JSON 行追加一条记录。
+func (fs *FileStore) AppendWAL(entry *model.PlayerEntry) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	if _, err := fs.walFile.Write(line); err != nil {
+		return err
+	}
+	return fs.walFile.Sync()
+}
+
+// LoadWAL 按写入顺序读出 WAL 中的全部记录。
+func (fs *FileStore) LoadWAL() ([]*model.PlayerEntry, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	f, err := os.Open(fs.walPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []*model.PlayerEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry model.PlayerEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, &entry)
+	}
+	return entries, scanner.Err()
+}
+
+// TruncateWAL 在快照完成后清空 WAL 文件。
+func (fs *FileStore) TruncateWAL() error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if err := fs.walFile.Truncate(0); err != nil {
+		return err
+	}
+	_, err := fs.walFile.Seek(0, 0)
+	return err
+}
+
+// Close 关闭 WAL 文件句柄。
+func (fs *FileStore) Close() error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return fs.walFile.Close()
+}
@@ -0,0 +1,165 @@
+package persistence
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"leader_board/leader_board/model"
+)
+
+// SQLStore 是基于 database/sql 的 Store 实现，快照存一张表（每次整体覆盖），
+// WAL 存另一张按自增序号排序的表。建表和读写语句按 SQLite 方言书写
+// （INTEGER PRIMARY KEY AUTOINCREMENT、? 占位符），其他方言的数据库不一定能直接使用。
+type SQLStore struct {
+	db *sql.DB
+}
+
+// NewSQLStore 基于已打开的 *sql.DB 创建 SQLStore，并确保所需的表已存在。
+func NewSQLStore(db *sql.DB) (*SQLStore, error) {
+	stmts := []string{
+		`CREATE TABLE IF NOT EXISTS leaderboard_snapshot (
+			player_id TEXT PRIMARY KEY,
+			score INTEGER NOT NULL,
+			timestamp INTEGER NOT NULL,
+			extra TEXT
+		)`,
+		`CREATE TABLE IF NOT EXISTS leaderboard_wal (
+			seq INTEGER PRIMARY KEY AUTOINCREMENT,
+			player_id TEXT NOT NULL,
+			score INTEGER NOT NULL,
+			timestamp INTEGER NOT NULL,
+			extra TEXT
+		)`,
+	}
+	for _, stmt := range stmts {
+		if _, err := db.Exec(stmt); err != nil {
+			return nil, err
+		}
+	}
+	return &SQLStore{db: db}, nil
+}
+
+// marshalExtra 把 Extra 编码成 JSON 文本存进 TEXT 列；Extra 为空时存 NULL，
+// 避免给绝大多数不用多维度分数的玩家都写一列没意义的 "{}"。
+func marshalExtra(extra map[string]float64) (sql.NullString, error) {
+	if len(extra) == 0 {
+		return sql.NullString{}, nil
+	}
+	raw, err := json.Marshal(extra)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(raw), Valid: true}, nil
+}
+
+// unmarshalExtra 是 marshalExtra 的逆操作。
+func unmarshalExtra(raw sql.NullString) (map[string]float64, error) {
+	if !raw.Valid {
+		return nil, nil
+	}
+	var extra map[string]float64
+	if err := json.Unmarshal([]byte(raw.String), &extra); err != nil {
+		return nil, err
+	}
+	return extra, nil
+}
+
+// SaveSnapshot 在一个事务内用 entries 整体覆盖快照表。
+func (ss *SQLStore) SaveSnapshot(entries []*model.PlayerEntry) error {
+	tx, err := ss.db.Begin()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM leaderboard_snapshot`); err != nil {
+		tx.Rollback()
+		return err
+	}
+	stmt, err := tx.Prepare(`INSERT INTO leaderboard_snapshot (player_id, score, timestamp, extra) VALUES (?, ?, ?, ?)`)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	for _, e := range entries {
+		extra, err := marshalExtra(e.Extra)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		if _, err := stmt.Exec(e.PlayerID, e.Score, e.Timestamp, extra); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// LoadSnapshot 读出快照表的全部记录。
+func (ss *SQLStore) LoadSnapshot() ([]*model.PlayerEntry, error) {
+	rows, err := ss.db.Query(`SELECT player_id, score, timestamp, extra FROM leaderboard_snapshot`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*model.PlayerEntry
+	for rows.Next() {
+		var e model.PlayerEntry
+		var extra sql.NullString
+		if err := rows.Scan(&e.PlayerID, &e.Score, &e.Timestamp, &extra); err != nil {
+			return nil, err
+		}
+		if e.Extra, err = unmarshalExtra(extra); err != nil {
+			return nil, err
+		}
+		entries = append(entries, &e)
+	}
+	return entries, rows.Err()
+}
+
+// AppendWAL 追加一条 WAL 记录。
+func (ss *SQLStore) AppendWAL(entry *model.PlayerEntry) error {
+	extra, err := marshalExtra(entry.Extra)
+	if err != nil {
+		return err
+	}
+	_, err = ss.db.Exec(
+		`INSERT INTO leaderboard_wal (player_id, score, timestamp, extra) VALUES (?, ?, ?, ?)`,
+		entry.PlayerID, entry.Score, entry.Timestamp, extra,
+	)
+	return err
+}
+
+// LoadWAL 按写入顺序（seq 升序）读出 WAL 中的全部记录。
+func (ss *SQLStore) LoadWAL() ([]*model.PlayerEntry, error) {
+	rows, err := ss.db.Query(`SELECT player_id, score, timestamp, extra FROM leaderboard_wal ORDER BY seq ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*model.PlayerEntry
+	for rows.Next() {
+		var e model.PlayerEntry
+		var extra sql.NullString
+		if err := rows.Scan(&e.PlayerID, &e.Score, &e.Timestamp, &extra); err != nil {
+			return nil, err
+		}
+		if e.Extra, err = unmarshalExtra(extra); err != nil {
+			return nil, err
+		}
+		entries = append(entries, &e)
+	}
+	return entries, rows.Err()
+}
+
+// TruncateWAL 在快照完成后清空 WAL 表。
+func (ss *SQLStore) TruncateWAL() error {
+	_, err := ss.db.Exec(`DELETE FROM leaderboard_wal`)
+	return err
+}
+
+// Close 关闭底层数据库连接。
+func (ss *SQLStore) Close() error {
+	return ss.db.Close()
+}
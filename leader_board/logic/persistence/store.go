@@ -0,0 +1,21 @@
+// Package persistence 为排行榜提供快照 + WAL 的持久化能力，
+// 使 LeaderboardService 能在进程重启后从磁盘或数据库恢复状态。
+package persistence
+
+import "leader_board/leader_board/model"
+
+// Store 是持久化后端需要实现的接口：一份全量快照加上快照之后的写前日志（WAL）。
+type Store interface {
+	// SaveSnapshot 保存一份全量快照，覆盖上一份。
+	SaveSnapshot(entries []*model.PlayerEntry) error
+	// LoadSnapshot 加载最近一份快照；从未保存过快照时返回 (nil, nil)。
+	LoadSnapshot() ([]*model.PlayerEntry, error)
+	// AppendWAL 追加一条写前日志记录。
+	AppendWAL(entry *model.PlayerEntry) error
+	// LoadWAL 加载最近一次 TruncateWAL 之后的全部 WAL 记录，按写入顺序排列。
+	LoadWAL() ([]*model.PlayerEntry, error)
+	// TruncateWAL 在快照完成后清空 WAL。
+	TruncateWAL() error
+	// Close 释放底层资源。
+	Close() error
+}
@@ -0,0 +1,280 @@
+// Package season 在 LeaderboardService 之上添加赛季周期（RankPeriod）概念：
+// 每个 category（如 "weekly"、"monthly"、"season-2024-Q1"）在任意时刻都有一个进行中的
+// 赛季，赛季结束后冻结、归档最终排名，再开启一个全新的空榜。
+package season
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"leader_board/leader_board/logic"
+	"leader_board/leader_board/logic/persistence"
+	"leader_board/leader_board/model"
+)
+
+// allRanksN 用于向 LeaderboardService.GetTopN 请求“全部名次”。
+const allRanksN = 1 << 30
+
+// Season 是一个赛季周期：固定的起止时间窗口加上独立的排行榜实例。
+// ID 和 Enabled 在赛季存续期间可被 EnableSeason/DisableSeason/RenameSeason 并发
+// 修改，同时又被 UpdateScore 并发读取，因此用 mu 单独保护，不归 SeasonManager.mu 管
+// （后者只保护 current/archive/stopFns 这几个 map 本身的增删）。Start/End 创建后不再
+// 改变，读取不需要加锁。
+type Season struct {
+	ID      string
+	Start   int64
+	End     int64
+	Enabled bool
+
+	mu      sync.RWMutex
+	service *logic.LeaderboardService
+}
+
+// isEnabled 返回赛季当前是否接受分数提交。
+func (s *Season) isEnabled() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.Enabled
+}
+
+// setEnabled 设置赛季是否接受分数提交。
+func (s *Season) setEnabled(enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Enabled = enabled
+}
+
+// id 返回赛季当前的 ID。
+func (s *Season) id() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.ID
+}
+
+// setID 修改赛季的 ID。
+func (s *Season) setID(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ID = id
+}
+
+// archivedSeason 是赛季结束归档后留存的记录；service 继续保留在内存中，
+// 以便在归档后收到的迟到分数（时间戳落在该赛季窗口内）仍能被正确路由和统计。
+type archivedSeason struct {
+	season *Season
+}
+
+// StoreFactory 为某个赛季 ID 创建一个持久化 Store，用于落地该赛季的最终排名；
+// 返回 nil, nil 表示该赛季不需要持久化归档。
+type StoreFactory func(seasonID string) (persistence.Store, error)
+
+// SeasonManager 管理多个 category 各自的当前赛季与历史归档。
+type SeasonManager struct {
+	mu       sync.RWMutex
+	current  map[string]*Season
+	archive  map[string]*archivedSeason
+	newStore StoreFactory
+	stopFns  map[string]func()
+}
+
+// NewSeasonManager 创建赛季管理器；newStore 为 nil 时归档只保存在内存中。
+func NewSeasonManager(newStore StoreFactory) *SeasonManager {
+	return &SeasonManager{
+		current: make(map[string]*Season),
+		archive: make(map[string]*archivedSeason),
+		stopFns: make(map[string]func()),
+		newStore: func(seasonID string) (persistence.Store, error) {
+			if newStore == nil {
+				return nil, nil
+			}
+			return newStore(seasonID)
+		},
+	}
+}
+
+// CreateSeason 为 category 开启一个新的赛季周期，替换该 category 当前进行中的赛季（如果有）。
+func (sm *SeasonManager) CreateSeason(category, seasonID string, start, end int64) *Season {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	season := &Season{
+		ID:      seasonID,
+		Start:   start,
+		End:     end,
+		Enabled: true,
+		service: logic.NewLeaderboardService(),
+	}
+	sm.current[category] = season
+	return season
+}
+
+// UpdateScore 提交一次分数变更。ts 落在该 category 当前赛季窗口之外时，
+// 会尝试路由到覆盖该时间戳的历史赛季；两者都不命中则返回 error。
+func (sm *SeasonManager) UpdateScore(category, playerId string, score int, ts int64) error {
+	sm.mu.RLock()
+	season, ok := sm.current[category]
+	sm.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("season: unknown category %q", category)
+	}
+	if !season.isEnabled() {
+		return fmt.Errorf("season: category %q is disabled", category)
+	}
+	if ts < season.Start || ts > season.End {
+		if sm.routeToHistorical(ts, playerId, score) {
+			return nil
+		}
+		return fmt.Errorf("season: timestamp %d outside active window of %q", ts, season.id())
+	}
+	season.service.UpdateScore(playerId, score, ts)
+	return nil
+}
+
+// routeToHistorical 尝试把一条分数变更写入覆盖该时间戳的历史赛季。
+func (sm *SeasonManager) routeToHistorical(ts int64, playerId string, score int) bool {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	for _, a := range sm.archive {
+		if ts >= a.season.Start && ts <= a.season.End {
+			a.season.service.UpdateScore(playerId, score, ts)
+			return true
+		}
+	}
+	return false
+}
+
+// GetTopN 返回 category 当前赛季的前 N 名。
+func (sm *SeasonManager) GetTopN(category string, n int) []model.RankInfo {
+	sm.mu.RLock()
+	season, ok := sm.current[category]
+	sm.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+	return season.service.GetTopN(n)
+}
+
+// GetHistoricalTopN 返回已归档赛季 seasonID 的前 N 名。
+func (sm *SeasonManager) GetHistoricalTopN(seasonID string, n int) []model.RankInfo {
+	sm.mu.RLock()
+	a, ok := sm.archive[seasonID]
+	sm.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+	return a.season.service.GetTopN(n)
+}
+
+// RolloverSeason 冻结 category 当前赛季、归档其最终排名，并以 nextID/nextStart/nextEnd
+// 开启一个全新的空榜。
+func (sm *SeasonManager) RolloverSeason(category, nextID string, nextStart, nextEnd int64) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	season, ok := sm.current[category]
+	if !ok {
+		return fmt.Errorf("season: unknown category %q", category)
+	}
+	season.setEnabled(false)
+
+	topN := season.service.GetTopN(allRanksN)
+	if store, err := sm.newStore(season.ID); err == nil && store != nil {
+		entries := make([]*model.PlayerEntry, 0, len(topN))
+		for _, r := range topN {
+			entries = append(entries, &model.PlayerEntry{PlayerID: r.PlayerID, Score: r.Score, Timestamp: r.Timestamp})
+		}
+		store.SaveSnapshot(entries)
+		store.Close()
+	}
+	sm.archive[season.ID] = &archivedSeason{season: season}
+
+	sm.current[category] = &Season{
+		ID:      nextID,
+		Start:   nextStart,
+		End:     nextEnd,
+		Enabled: true,
+		service: logic.NewLeaderboardService(),
+	}
+	return nil
+}
+
+// StartAutoRollover 启动一个按 period 周期自动 Rollover 的后台协程，
+// 新赛季 ID 由 nextID 基于上一个赛季 ID 生成。返回的 stop 函数用于停止该协程。
+func (sm *SeasonManager) StartAutoRollover(category string, period time.Duration, nextID func(prevID string) string) func() {
+	stop := make(chan struct{})
+	ticker := time.NewTicker(period)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case now := <-ticker.C:
+				sm.mu.RLock()
+				season, ok := sm.current[category]
+				sm.mu.RUnlock()
+				if !ok {
+					continue
+				}
+				start := now.Unix()
+				sm.RolloverSeason(category, nextID(season.id()), start, start+int64(period.Seconds()))
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	stopFn := func() { close(stop) }
+	sm.mu.Lock()
+	sm.stopFns[category] = stopFn
+	sm.mu.Unlock()
+	return stopFn
+}
+
+// ------------------------------------以下为管理后台接口------------------------------------
+
+// ListSeasons 列出所有 category 当前进行中的赛季。
+func (sm *SeasonManager) ListSeasons() []*Season {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	res := make([]*Season, 0, len(sm.current))
+	for _, s := range sm.current {
+		res = append(res, s)
+	}
+	return res
+}
+
+// EnableSeason 启用 category 当前的赛季，使其重新接受分数提交。
+func (sm *SeasonManager) EnableSeason(category string) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	season, ok := sm.current[category]
+	if !ok {
+		return fmt.Errorf("season: unknown category %q", category)
+	}
+	season.setEnabled(true)
+	return nil
+}
+
+// DisableSeason 禁用 category 当前的赛季，使其暂时拒绝分数提交。
+func (sm *SeasonManager) DisableSeason(category string) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	season, ok := sm.current[category]
+	if !ok {
+		return fmt.Errorf("season: unknown category %q", category)
+	}
+	season.setEnabled(false)
+	return nil
+}
+
+// RenameSeason 修改 category 当前赛季的 ID。
+func (sm *SeasonManager) RenameSeason(category, newID string) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	season, ok := sm.current[category]
+	if !ok {
+		return fmt.Errorf("season: unknown category %q", category)
+	}
+	season.setID(newID)
+	return nil
+}
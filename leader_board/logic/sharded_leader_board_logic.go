@@ -0,0 +1,163 @@
+package logic
+
+import (
+	"container/heap"
+	"hash/fnv"
+
+	"leader_board/leader_board/model"
+)
+
+// ShardedLeaderboardService 把玩家按 PlayerID 哈希分散到多个独立加锁的 LeaderboardService，
+// 缓解高并发写入下单把 sync.RWMutex 的瓶颈；需要全局视图的读操作在各分片间做归并。
+// 所有分片必须使用同一个 Comparator，否则归并出来的全局名次没有意义。
+type ShardedLeaderboardService struct {
+	shards []*LeaderboardService
+	cmp    model.Comparator
+}
+
+// NewShardedLeaderboardService 创建一个拥有 shards 个分片的排行榜服务，排序沿用
+// NewLeaderboardService 一直以来的行为（分数越高排名越靠前，同分时间戳早者靠前）。
+func NewShardedLeaderboardService(shards int) *ShardedLeaderboardService {
+	return NewShardedLeaderboardServiceWithComparator(shards, model.ScoreDescTimeAsc)
+}
+
+// NewShardedLeaderboardServiceWithComparator 创建一个按 cmp 排序的分片排行榜服务。
+func NewShardedLeaderboardServiceWithComparator(shards int, cmp model.Comparator) *ShardedLeaderboardService {
+	s := &ShardedLeaderboardService{shards: make([]*LeaderboardService, shards), cmp: cmp}
+	for i := range s.shards {
+		s.shards[i] = NewLeaderboardServiceWithComparator(cmp)
+	}
+	return s
+}
+
+// shardIndex 把 playerId 哈希到某个分片。
+func (s *ShardedLeaderboardService) shardIndex(playerId string) int {
+	h := fnv.New32a()
+	h.Write([]byte(playerId))
+	return int(h.Sum32() % uint32(len(s.shards)))
+}
+
+// 更新玩家分数，写入操作只加锁其所属分片。
+func (s *ShardedLeaderboardService) UpdateScore(playerId string, score int, timestamp int64) {
+	s.shards[s.shardIndex(playerId)].UpdateScore(playerId, score, timestamp)
+}
+
+// GetPlayerRank 先取玩家所属分片内的本地名次，再用 CountAbove 向其余分片询问
+// 排在该玩家之前的玩家数，累加得到全局名次。CountAbove 与本地排名用的是同一套
+// 打破同组并列的规则（Comparator 优先，PlayerID 兜底），否则两个分数、时间戳都
+// 相同的玩家会因为落在同一分片还是不同分片而算出不一样的名次。
+func (s *ShardedLeaderboardService) GetPlayerRank(playerId string) *model.RankInfo {
+	idx := s.shardIndex(playerId)
+	local := s.shards[idx].GetPlayerRank(playerId)
+	if local == nil {
+		return nil
+	}
+
+	key := &model.PlayerEntry{PlayerID: local.PlayerID, Score: local.Score, Timestamp: local.Timestamp, Extra: local.Extra}
+	above := local.Rank - 1
+	for i, shard := range s.shards {
+		if i == idx {
+			continue
+		}
+		above += shard.CountAbove(key)
+	}
+	local.Rank = above + 1
+	return local
+}
+
+// mergeItem 是归并堆中的一个游标：当前分片已取出的名次信息，以及该分片下一个候选的下标。
+type mergeItem struct {
+	info    model.RankInfo
+	shard   int
+	nextIdx int
+}
+
+// mergeHeap 按分片服务共享的 Comparator 对 mergeItem 排序，Comparator 判为同组时
+// 以 PlayerID 兜底——这与 zset.less（GetRank/CountAbove 据此定位节点）的打破并列
+// 规则完全一致，否则归并出来的 GetTopN 顺序会跟 GetPlayerRank 对同一批玩家算出
+// 的名次对不上。
+type mergeHeap struct {
+	items []mergeItem
+	cmp   model.Comparator
+}
+
+func (h mergeHeap) Len() int { return len(h.items) }
+func (h mergeHeap) Less(i, j int) bool {
+	a := rankInfoEntry(h.items[i].info)
+	b := rankInfoEntry(h.items[j].info)
+	switch c := h.cmp(a, b); {
+	case c < 0:
+		return true
+	case c > 0:
+		return false
+	default:
+		return a.PlayerID < b.PlayerID
+	}
+}
+func (h mergeHeap) Swap(i, j int) { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *mergeHeap) Push(x interface{}) {
+	h.items = append(h.items, x.(mergeItem))
+}
+func (h *mergeHeap) Pop() interface{} {
+	old := h.items
+	n := len(old)
+	item := old[n-1]
+	h.items = old[:n-1]
+	return item
+}
+
+func rankInfoEntry(r model.RankInfo) *model.PlayerEntry {
+	return &model.PlayerEntry{PlayerID: r.PlayerID, Score: r.Score, Timestamp: r.Timestamp, Extra: r.Extra}
+}
+
+// GetTopN 做一次 k-way 归并：全局前 N 名必然落在各分片各自的前 N 名之内，
+// 所以只需取每个分片的本地前 N 名，再用最小堆按共享 Comparator 依次弹出。
+func (s *ShardedLeaderboardService) GetTopN(n int) []model.RankInfo {
+	perShard := make([][]model.RankInfo, len(s.shards))
+	for i, shard := range s.shards {
+		perShard[i] = shard.GetTopN(n)
+	}
+
+	h := &mergeHeap{items: make([]mergeItem, 0, len(s.shards)), cmp: s.cmp}
+	for i, list := range perShard {
+		if len(list) > 0 {
+			h.items = append(h.items, mergeItem{info: list[0], shard: i, nextIdx: 1})
+		}
+	}
+	heap.Init(h)
+
+	res := make([]model.RankInfo, 0, n)
+	for len(res) < n && h.Len() > 0 {
+		top := heap.Pop(h).(mergeItem)
+		top.info.Rank = len(res) + 1
+		res = append(res, top.info)
+
+		list := perShard[top.shard]
+		if top.nextIdx < len(list) {
+			heap.Push(h, mergeItem{info: list[top.nextIdx], shard: top.shard, nextIdx: top.nextIdx + 1})
+		}
+	}
+	return res
+}
+
+// GetPlayerRankRange 先算出玩家的全局名次，再取一段足够覆盖 [rank-rng, rank+rng] 的全局前 N 名切片出来。
+func (s *ShardedLeaderboardService) GetPlayerRankRange(playerId string, rng int) []model.RankInfo {
+	info := s.GetPlayerRank(playerId)
+	if info == nil {
+		return nil
+	}
+
+	start := info.Rank - 1 - rng
+	if start < 0 {
+		start = 0
+	}
+	top := s.GetTopN(info.Rank + rng)
+	end := info.Rank - 1 + rng
+	if end >= len(top) {
+		end = len(top) - 1
+	}
+	if start > end {
+		return nil
+	}
+	return top[start : end+1]
+}
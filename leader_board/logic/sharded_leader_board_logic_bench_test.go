@@ -0,0 +1,38 @@
+package logic
+
+import (
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+// BenchmarkSingleLockUpdateScoreParallel 模拟多个 goroutine 并发写入同一把
+// sync.RWMutex 保护的排行榜，作为 ShardedLeaderboardService 的吞吐基线。
+func BenchmarkSingleLockUpdateScoreParallel(b *testing.B) {
+	lb := NewLeaderboardService()
+	var counter int64
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			i := atomic.AddInt64(&counter, 1)
+			playerId := "player-" + strconv.FormatInt(i%10000, 10)
+			lb.UpdateScore(playerId, int(i), i)
+		}
+	})
+}
+
+// BenchmarkShardedUpdateScoreParallel 在同样的并发写入负载下对比分片之后的吞吐。
+func BenchmarkShardedUpdateScoreParallel(b *testing.B) {
+	s := NewShardedLeaderboardService(16)
+	var counter int64
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			i := atomic.AddInt64(&counter, 1)
+			playerId := "player-" + strconv.FormatInt(i%10000, 10)
+			s.UpdateScore(playerId, int(i), i)
+		}
+	})
+}
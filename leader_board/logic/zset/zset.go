@@ -0,0 +1,251 @@
+// Package zset 实现一个类似 Redis ZSET 的跳表，排序由外部注入的 model.Comparator 决定，
+// Insert/Delete/GetRank 均为 O(log N)，区间查询为 O(log N + M)。
+package zset
+
+import (
+	"math/rand"
+
+	"leader_board/leader_board/model"
+)
+
+const (
+	maxLevel = 32
+	p        = 0.25
+)
+
+type levelNode struct {
+	forward *Node
+	span    int
+}
+
+// Node 是跳表中的一个节点，对外暴露其承载的 PlayerEntry。
+type Node struct {
+	entry    *model.PlayerEntry
+	backward *Node
+	level    []levelNode
+}
+
+// Entry 返回该节点对应的玩家条目。
+func (n *Node) Entry() *model.PlayerEntry {
+	return n.entry
+}
+
+// Skiplist 是按排名顺序维护 PlayerEntry 的跳表，顺序由构造时传入的 Comparator 决定。
+type Skiplist struct {
+	header *Node
+	tail   *Node
+	length int
+	level  int
+	cmp    model.Comparator
+}
+
+// NewSkiplist 创建一个按 cmp 排序的空跳表。
+func NewSkiplist(cmp model.Comparator) *Skiplist {
+	return &Skiplist{
+		header: newNode(maxLevel, nil),
+		level:  1,
+		cmp:    cmp,
+	}
+}
+
+func newNode(level int, entry *model.PlayerEntry) *Node {
+	return &Node{
+		entry: entry,
+		level: make([]levelNode, level),
+	}
+}
+
+func randomLevel() int {
+	lvl := 1
+	for rand.Float64() < p && lvl < maxLevel {
+		lvl++
+	}
+	return lvl
+}
+
+// less 是跳表内部用于定位/排序的全序关系：先按 cmp 排，cmp 判为同组（如同分）时
+// 再按 PlayerID 兜底排序，从而保证每个玩家在跳表中的位置唯一、可被精确定位。
+func (zs *Skiplist) less(a, b *model.PlayerEntry) bool {
+	switch c := zs.cmp(a, b); {
+	case c < 0:
+		return true
+	case c > 0:
+		return false
+	default:
+		return a.PlayerID < b.PlayerID
+	}
+}
+
+// sameKey 判断两个 PlayerEntry 是否代表同一个玩家；配合 less 的遍历使用即可精确定位节点。
+func sameKey(a, b *model.PlayerEntry) bool {
+	return a.PlayerID == b.PlayerID
+}
+
+// Len 返回跳表中元素个数。
+func (zs *Skiplist) Len() int {
+	return zs.length
+}
+
+// Insert 插入一个新的 PlayerEntry，返回承载它的节点。
+func (zs *Skiplist) Insert(entry *model.PlayerEntry) *Node {
+	update := make([]*Node, maxLevel)
+	rank := make([]int, maxLevel)
+	x := zs.header
+	for i := zs.level - 1; i >= 0; i-- {
+		if i == zs.level-1 {
+			rank[i] = 0
+		} else {
+			rank[i] = rank[i+1]
+		}
+		for x.level[i].forward != nil && zs.less(x.level[i].forward.entry, entry) {
+			rank[i] += x.level[i].span
+			x = x.level[i].forward
+		}
+		update[i] = x
+	}
+
+	lvl := randomLevel()
+	if lvl > zs.level {
+		for i := zs.level; i < lvl; i++ {
+			rank[i] = 0
+			update[i] = zs.header
+			update[i].level[i].span = zs.length
+		}
+		zs.level = lvl
+	}
+
+	x = newNode(lvl, entry)
+	for i := 0; i < lvl; i++ {
+		x.level[i].forward = update[i].level[i].forward
+		update[i].level[i].forward = x
+		x.level[i].span = update[i].level[i].span - (rank[0] - rank[i])
+		update[i].level[i].span = rank[0] - rank[i] + 1
+	}
+	for i := lvl; i < zs.level; i++ {
+		update[i].level[i].span++
+	}
+
+	if update[0] != zs.header {
+		x.backward = update[0]
+	}
+	if x.level[0].forward != nil {
+		x.level[0].forward.backward = x
+	} else {
+		zs.tail = x
+	}
+	zs.length++
+	return x
+}
+
+// Delete 定位并移除 entry 对应的节点（按 entry 当前的排序字段定位），返回是否命中。
+func (zs *Skiplist) Delete(entry *model.PlayerEntry) bool {
+	update := make([]*Node, maxLevel)
+	x := zs.header
+	for i := zs.level - 1; i >= 0; i-- {
+		for x.level[i].forward != nil && zs.less(x.level[i].forward.entry, entry) {
+			x = x.level[i].forward
+		}
+		update[i] = x
+	}
+	x = x.level[0].forward
+	if x != nil && sameKey(x.entry, entry) {
+		zs.deleteNode(x, update)
+		return true
+	}
+	return false
+}
+
+func (zs *Skiplist) deleteNode(x *Node, update []*Node) {
+	for i := 0; i < zs.level; i++ {
+		if update[i].level[i].forward == x {
+			update[i].level[i].span += x.level[i].span - 1
+			update[i].level[i].forward = x.level[i].forward
+		} else {
+			update[i].level[i].span--
+		}
+	}
+	if x.level[0].forward != nil {
+		x.level[0].forward.backward = x.backward
+	} else {
+		zs.tail = x.backward
+	}
+	for zs.level > 1 && zs.header.level[zs.level-1].forward == nil {
+		zs.level--
+	}
+	zs.length--
+}
+
+// GetRank 返回 entry 对应节点的 0-based 排名。
+func (zs *Skiplist) GetRank(entry *model.PlayerEntry) (int, bool) {
+	x := zs.header
+	rank := 0
+	for i := zs.level - 1; i >= 0; i-- {
+		for x.level[i].forward != nil &&
+			!sameKey(x.level[i].forward.entry, entry) &&
+			zs.less(x.level[i].forward.entry, entry) {
+			rank += x.level[i].span
+			x = x.level[i].forward
+		}
+		if x.level[i].forward != nil && sameKey(x.level[i].forward.entry, entry) {
+			return rank + x.level[i].span - 1, true
+		}
+	}
+	return 0, false
+}
+
+// CountAbove 返回按 less（与 GetRank 定位节点时同一套总序：先比较 Comparator，
+// 再用 PlayerID 兜底打破同组并列）排在 entry 之前的节点数，借助 span 在 O(log N) 内完成。
+// 之所以不能只用原始 Comparator，是因为 GetRank 对同组条目是按 PlayerID 兜底排序的
+// ——分片排行榜要靠 CountAbove 在其余分片里统计"排在我前面的人数"来拼出全局名次，
+// 如果两者的同组打破并列规则不一致，就会出现同一份数据在单分片和多分片下名次不一致。
+func (zs *Skiplist) CountAbove(entry *model.PlayerEntry) int {
+	x := zs.header
+	count := 0
+	for i := zs.level - 1; i >= 0; i-- {
+		for x.level[i].forward != nil && zs.less(x.level[i].forward.entry, entry) {
+			count += x.level[i].span
+			x = x.level[i].forward
+		}
+	}
+	return count
+}
+
+// GetByRank 返回 0-based 排名对应的节点，排名越界时返回 nil。
+func (zs *Skiplist) GetByRank(rank int) *Node {
+	if rank < 0 || rank >= zs.length {
+		return nil
+	}
+	target := rank + 1
+	traversed := 0
+	x := zs.header
+	for i := zs.level - 1; i >= 0; i-- {
+		for x.level[i].forward != nil && traversed+x.level[i].span <= target {
+			traversed += x.level[i].span
+			x = x.level[i].forward
+		}
+		if traversed == target {
+			return x
+		}
+	}
+	return nil
+}
+
+// GetRange 返回 [start, end] 闭区间（0-based，含端点）内按排名排序的节点。
+func (zs *Skiplist) GetRange(start, end int) []*Node {
+	if start < 0 {
+		start = 0
+	}
+	if end >= zs.length {
+		end = zs.length - 1
+	}
+	if zs.length == 0 || start > end {
+		return nil
+	}
+	x := zs.GetByRank(start)
+	res := make([]*Node, 0, end-start+1)
+	for i := start; i <= end && x != nil; i++ {
+		res = append(res, x)
+		x = x.level[0].forward
+	}
+	return res
+}
@@ -0,0 +1,106 @@
+package zset
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"leader_board/leader_board/model"
+)
+
+// sliceRanking 是替换前"有序切片 + sort.Search"实现的精简复刻，Insert 需要 O(N)
+// 的数组搬移、rank 需要线性扫描，仅用于基准测试中与跳表实现做对比。
+type sliceRanking struct {
+	entries []*model.PlayerEntry
+	cmp     model.Comparator
+}
+
+func newSliceRanking(cmp model.Comparator) *sliceRanking {
+	return &sliceRanking{cmp: cmp}
+}
+
+func (s *sliceRanking) insert(entry *model.PlayerEntry) {
+	idx := sort.Search(len(s.entries), func(i int) bool {
+		return s.cmp(s.entries[i], entry) > 0
+	})
+	s.entries = append(s.entries, nil)
+	copy(s.entries[idx+1:], s.entries[idx:])
+	s.entries[idx] = entry
+}
+
+func (s *sliceRanking) rank(entry *model.PlayerEntry) (int, bool) {
+	for i, e := range s.entries {
+		if e.PlayerID == entry.PlayerID {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// benchPlayers 是基准测试里预热跳表/切片用的玩家规模，覆盖请求里要求的 100k+ 量级。
+const benchPlayers = 100_000
+
+func genEntries(start, n int) []*model.PlayerEntry {
+	entries := make([]*model.PlayerEntry, n)
+	for i := 0; i < n; i++ {
+		entries[i] = &model.PlayerEntry{
+			PlayerID:  fmt.Sprintf("player-%d", start+i),
+			Score:     rand.Intn(1_000_000),
+			Timestamp: int64(start + i),
+		}
+	}
+	return entries
+}
+
+func BenchmarkSkiplistInsert(b *testing.B) {
+	zs := NewSkiplist(model.ScoreDescTimeAsc)
+	for _, e := range genEntries(0, benchPlayers) {
+		zs.Insert(e)
+	}
+	extra := genEntries(benchPlayers, b.N)
+
+	b.ResetTimer()
+	for _, e := range extra {
+		zs.Insert(e)
+	}
+}
+
+func BenchmarkSliceInsert(b *testing.B) {
+	sr := newSliceRanking(model.ScoreDescTimeAsc)
+	for _, e := range genEntries(0, benchPlayers) {
+		sr.insert(e)
+	}
+	extra := genEntries(benchPlayers, b.N)
+
+	b.ResetTimer()
+	for _, e := range extra {
+		sr.insert(e)
+	}
+}
+
+func BenchmarkSkiplistGetRank(b *testing.B) {
+	zs := NewSkiplist(model.ScoreDescTimeAsc)
+	entries := genEntries(0, benchPlayers)
+	for _, e := range entries {
+		zs.Insert(e)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		zs.GetRank(entries[i%len(entries)])
+	}
+}
+
+func BenchmarkSliceGetRank(b *testing.B) {
+	sr := newSliceRanking(model.ScoreDescTimeAsc)
+	entries := genEntries(0, benchPlayers)
+	for _, e := range entries {
+		sr.insert(e)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sr.rank(entries[i%len(entries)])
+	}
+}
@@ -0,0 +1,67 @@
+package model
+
+// Comparator 决定两个 PlayerEntry 在排行榜中的先后顺序：a 应排在 b 前面时返回负数，
+// a 应排在 b 后面时返回正数，两者属于同一名次分组（如同分）时返回 0。
+//
+// 实现者应当保证这是一个一致的全序关系（至少对分组而言传递、反对称），
+// 密集排名（dense rank）会直接用 Comparator(a, b) == 0 来判断 a、b 是否同组。
+type Comparator func(a, b *PlayerEntry) int
+
+// ScoreDescTimeAsc 是默认排序：分数越高排名越靠前，分数相同则时间戳越早排名越靠前。
+// 这是 NewLeaderboardService() 一直以来的行为。
+func ScoreDescTimeAsc(a, b *PlayerEntry) int {
+	if a.Score != b.Score {
+		if a.Score > b.Score {
+			return -1
+		}
+		return 1
+	}
+	if a.Timestamp != b.Timestamp {
+		if a.Timestamp < b.Timestamp {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
+
+// ScoreAscTimeAsc 是反向排序：分数越低排名越靠前，分数相同则时间戳越早排名越靠前。
+func ScoreAscTimeAsc(a, b *PlayerEntry) int {
+	if a.Score != b.Score {
+		if a.Score < b.Score {
+			return -1
+		}
+		return 1
+	}
+	if a.Timestamp != b.Timestamp {
+		if a.Timestamp < b.Timestamp {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
+
+// Lexicographic 按给定的 Extra 字段依次降序比较（字段值越大排名越靠前），
+// 字段全部相同时退回按时间戳升序比较，例如 Lexicographic("wins", "kd_ratio")
+// 对应“胜场数 desc, KD desc, 时间戳 asc”。
+func Lexicographic(fields ...string) Comparator {
+	return func(a, b *PlayerEntry) int {
+		for _, field := range fields {
+			av, bv := a.Extra[field], b.Extra[field]
+			if av != bv {
+				if av > bv {
+					return -1
+				}
+				return 1
+			}
+		}
+		if a.Timestamp != b.Timestamp {
+			if a.Timestamp < b.Timestamp {
+				return -1
+			}
+			return 1
+		}
+		return 0
+	}
+}
@@ -5,10 +5,15 @@ type RankInfo struct {
 	Score     int
 	Rank      int
 	Timestamp int64
+	// Extra 携带多维度排序用到的附加分数（如胜场数、KD 等），与 PlayerEntry.Extra 对应。
+	Extra map[string]float64
 }
 
 type PlayerEntry struct {
 	PlayerID  string
 	Score     int
 	Timestamp int64
+	// Extra 是可选的多维度分数，配合 Comparator（如 Lexicographic）实现
+	// "胜场 desc, KD desc, 时间戳 asc" 这类复合排序。
+	Extra map[string]float64
 }
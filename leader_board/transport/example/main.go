@@ -0,0 +1,88 @@
+// Command example 是 transport.HTTPHandler 暴露的 REST/JSON 接口的一个最小示例客户端，
+// 演示如何更新分数、读取排行榜以及它的密集排名变体。运行前先启动一个注册了
+// HTTPHandler 路由的服务器（参见 transport.NewHTTPHandler），默认假设它监听在
+// localhost:8080。
+//
+// gRPC 接口需要先用 `go generate` 生成 leader_board/transport/pb 包才能使用，
+// 这里只演示 REST 路径；WebSocket 的 SubscribeTopN 用法见 transport 包自身的
+// 集成测试。
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+type updateScoreRequest struct {
+	PlayerID  string `json:"player_id"`
+	Score     int    `json:"score"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+func main() {
+	addr := flag.String("addr", "http://localhost:8080", "leaderboard HTTP 服务的 base URL")
+	flag.Parse()
+
+	client := &http.Client{}
+	updateScore(client, *addr, "alice", 120, 1)
+	updateScore(client, *addr, "bob", 95, 2)
+	updateScore(client, *addr, "carol", 120, 3)
+
+	fmt.Println("top 10:")
+	printJSON(get(client, *addr+"/v1/top?n=10"))
+
+	fmt.Println("dense top 10:")
+	printJSON(get(client, *addr+"/v1/top-dense?n=10"))
+
+	fmt.Println("alice's rank:")
+	printJSON(get(client, *addr+"/v1/rank?player_id=alice"))
+
+	fmt.Println("alice's rank range (+/-1):")
+	printJSON(get(client, *addr+"/v1/rank-range?player_id=alice&range=1"))
+}
+
+func updateScore(client *http.Client, addr, playerId string, score int, ts int64) {
+	body, err := json.Marshal(updateScoreRequest{PlayerID: playerId, Score: score, Timestamp: ts})
+	if err != nil {
+		log.Fatalf("marshal update score request: %v", err)
+	}
+	resp, err := client.Post(addr+"/v1/score", "application/json", bytes.NewReader(body))
+	if err != nil {
+		log.Fatalf("POST /v1/score: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		log.Fatalf("POST /v1/score: unexpected status %d", resp.StatusCode)
+	}
+}
+
+func get(client *http.Client, url string) []byte {
+	resp, err := client.Get(url)
+	if err != nil {
+		log.Fatalf("GET %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
+		log.Fatalf("read response body: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func printJSON(raw []byte) {
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		fmt.Println(string(raw))
+		return
+	}
+	pretty, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		fmt.Println(string(raw))
+		return
+	}
+	fmt.Println(string(pretty))
+}
@@ -0,0 +1,132 @@
+// Package transport 把 LeaderboardService 以 REST/JSON、gRPC 和 WebSocket 的形式
+// 对外暴露，并通过一个 Hub 在分数变化时把 top-N 增量推送给订阅者。
+package transport
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"leader_board/leader_board/logic"
+)
+
+// HTTPHandler 把 LeaderboardService 包装成一组 REST/JSON 接口。
+type HTTPHandler struct {
+	svc *logic.LeaderboardService
+	hub *Hub
+}
+
+// NewHTTPHandler 创建 REST handler；hub 为 nil 时 /v1/subscribe 会返回 501。
+func NewHTTPHandler(svc *logic.LeaderboardService, hub *Hub) *HTTPHandler {
+	return &HTTPHandler{svc: svc, hub: hub}
+}
+
+// RegisterRoutes 把所有接口注册到 mux 上。
+func (h *HTTPHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/v1/score", h.handleUpdateScore)
+	mux.HandleFunc("/v1/rank", h.handleGetPlayerRank)
+	mux.HandleFunc("/v1/top", h.handleGetTopN)
+	mux.HandleFunc("/v1/rank-range", h.handleGetPlayerRankRange)
+	mux.HandleFunc("/v1/top-dense", h.handleGetDenseTopN)
+	mux.HandleFunc("/v1/rank-range-dense", h.handleGetPlayerDenseRankRange)
+	mux.HandleFunc("/v1/subscribe", h.handleSubscribeTopN)
+}
+
+type updateScoreRequest struct {
+	PlayerID  string `json:"player_id"`
+	Score     int    `json:"score"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+func (h *HTTPHandler) handleUpdateScore(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req updateScoreRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	h.svc.UpdateScore(req.PlayerID, req.Score, req.Timestamp)
+	if h.hub != nil {
+		h.hub.OnScoreChange()
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *HTTPHandler) handleGetPlayerRank(w http.ResponseWriter, r *http.Request) {
+	playerId := r.URL.Query().Get("player_id")
+	rank := h.svc.GetPlayerRank(playerId)
+	if rank == nil {
+		http.Error(w, "player not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, rank)
+}
+
+func (h *HTTPHandler) handleGetTopN(w http.ResponseWriter, r *http.Request) {
+	n, err := strconv.Atoi(r.URL.Query().Get("n"))
+	if err != nil || n <= 0 {
+		http.Error(w, "invalid n", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, h.svc.GetTopN(n))
+}
+
+func (h *HTTPHandler) handleGetPlayerRankRange(w http.ResponseWriter, r *http.Request) {
+	playerId := r.URL.Query().Get("player_id")
+	rng, err := strconv.Atoi(r.URL.Query().Get("range"))
+	if err != nil {
+		http.Error(w, "invalid range", http.StatusBadRequest)
+		return
+	}
+	res := h.svc.GetPlayerRankRange(playerId, rng)
+	if res == nil {
+		http.Error(w, "player not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, res)
+}
+
+func (h *HTTPHandler) handleGetDenseTopN(w http.ResponseWriter, r *http.Request) {
+	n, err := strconv.Atoi(r.URL.Query().Get("n"))
+	if err != nil || n <= 0 {
+		http.Error(w, "invalid n", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, h.svc.GetDenseTopN(n))
+}
+
+func (h *HTTPHandler) handleGetPlayerDenseRankRange(w http.ResponseWriter, r *http.Request) {
+	playerId := r.URL.Query().Get("player_id")
+	rng, err := strconv.Atoi(r.URL.Query().Get("range"))
+	if err != nil {
+		http.Error(w, "invalid range", http.StatusBadRequest)
+		return
+	}
+	res := h.svc.GetPlayerDenseRankRange(playerId, rng)
+	if res == nil {
+		http.Error(w, "player not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, res)
+}
+
+func (h *HTTPHandler) handleSubscribeTopN(w http.ResponseWriter, r *http.Request) {
+	n, err := strconv.Atoi(r.URL.Query().Get("n"))
+	if err != nil || n <= 0 {
+		http.Error(w, "invalid n", http.StatusBadRequest)
+		return
+	}
+	if h.hub == nil {
+		http.Error(w, "subscriptions not enabled", http.StatusNotImplemented)
+		return
+	}
+	h.hub.ServeSubscribeTopN(n)(w, r)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
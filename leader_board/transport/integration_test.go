@@ -0,0 +1,204 @@
+package transport
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"leader_board/leader_board/logic"
+)
+
+// TestHTTPIntegration 端到端地跑一遍 REST/JSON 接口：提交分数、读取 top-N、
+// 密集排名变体、单个玩家的名次以及名次区间，确认它们经过真实的 HTTP 往返后
+// 仍然一致。
+func TestHTTPIntegration(t *testing.T) {
+	svc := logic.NewLeaderboardService()
+	h := NewHTTPHandler(svc, nil)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := srv.Client()
+	postScore(t, client, srv.URL, "alice", 120, 1)
+	postScore(t, client, srv.URL, "bob", 95, 2)
+	// carol 的分数和时间戳都跟 alice 相同，在默认的 ScoreDescTimeAsc 比较器下
+	// 是真正的同组（Comparator(alice, carol) == 0），用来验证密集排名会并列。
+	postScore(t, client, srv.URL, "carol", 120, 1)
+
+	var top []map[string]interface{}
+	getJSON(t, client, srv.URL+"/v1/top?n=10", &top)
+	if len(top) != 3 || top[0]["PlayerID"] != "alice" {
+		t.Fatalf("unexpected /v1/top response: %+v", top)
+	}
+
+	var dense []map[string]interface{}
+	getJSON(t, client, srv.URL+"/v1/top-dense?n=10", &dense)
+	if len(dense) != 3 {
+		t.Fatalf("unexpected /v1/top-dense response: %+v", dense)
+	}
+	// alice 和 carol 同分(120)，密集排名下并列第 1，bob 紧随其后是第 2，
+	// 而不是因为有人并列就跳到第 3。
+	if dense[0]["Rank"].(float64) != 1 || dense[1]["Rank"].(float64) != 1 || dense[2]["Rank"].(float64) != 2 {
+		t.Fatalf("unexpected dense ranks: %+v", dense)
+	}
+
+	var rank map[string]interface{}
+	getJSON(t, client, srv.URL+"/v1/rank?player_id=alice", &rank)
+	if rank["Rank"].(float64) != 1 {
+		t.Fatalf("unexpected /v1/rank response: %+v", rank)
+	}
+
+	var rangeRes []map[string]interface{}
+	getJSON(t, client, srv.URL+"/v1/rank-range?player_id=bob&range=1", &rangeRes)
+	if len(rangeRes) != 2 {
+		t.Fatalf("unexpected /v1/rank-range response: %+v", rangeRes)
+	}
+
+	var denseRangeRes []map[string]interface{}
+	getJSON(t, client, srv.URL+"/v1/rank-range-dense?player_id=bob&range=1", &denseRangeRes)
+	if len(denseRangeRes) == 0 {
+		t.Fatalf("unexpected /v1/rank-range-dense response: %+v", denseRangeRes)
+	}
+}
+
+// TestWebSocketSubscribeIntegration 端到端地跑一遍 WebSocket 推送：建立订阅后提交一次
+// 分数变更，确认订阅者确实收到了一帧携带该变更的 top-N 增量。
+func TestWebSocketSubscribeIntegration(t *testing.T) {
+	svc := logic.NewLeaderboardService()
+	hub := NewHub(svc)
+	h := NewHTTPHandler(svc, hub)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	addr := strings.TrimPrefix(srv.URL, "http://")
+	conn, r, err := dialWebSocket(addr, "/v1/subscribe?n=3")
+	if err != nil {
+		t.Fatalf("dialWebSocket: %v", err)
+	}
+	defer conn.Close()
+
+	// 订阅建立时榜单还是空的，没有增量可推；提交一次分数变更之后才会收到第一帧。
+	svc.UpdateScore("alice", 100, 1)
+	hub.OnScoreChange()
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	payload, err := readTextFrame(r)
+	if err != nil {
+		t.Fatalf("readTextFrame: %v", err)
+	}
+
+	var delta TopNDelta
+	if err := json.Unmarshal(payload, &delta); err != nil {
+		t.Fatalf("unmarshal delta: %v", err)
+	}
+	if len(delta.Added) != 1 || delta.Added[0].PlayerID != "alice" {
+		t.Fatalf("unexpected delta: %+v", delta)
+	}
+}
+
+func postScore(t *testing.T, client *http.Client, baseURL, playerId string, score int, ts int64) {
+	t.Helper()
+	body, err := json.Marshal(updateScoreRequest{PlayerID: playerId, Score: score, Timestamp: ts})
+	if err != nil {
+		t.Fatalf("marshal update score request: %v", err)
+	}
+	resp, err := client.Post(baseURL+"/v1/score", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("POST /v1/score: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("POST /v1/score: unexpected status %d", resp.StatusCode)
+	}
+}
+
+func getJSON(t *testing.T, client *http.Client, url string, out interface{}) {
+	t.Helper()
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET %s: unexpected status %d", url, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		t.Fatalf("decode response from %s: %v", url, err)
+	}
+}
+
+// dialWebSocket 是测试专用的最小 WebSocket 客户端握手实现，只够触发服务端的
+// upgradeWebSocket 并拿到一条可读写的连接，用于验证 Hub 的推送行为。
+func dialWebSocket(addr, path string) (net.Conn, *bufio.Reader, error) {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return nil, nil, err
+	}
+	key := base64.StdEncoding.EncodeToString([]byte("integration-test-key-0001"))
+	req := "GET " + path + " HTTP/1.1\r\n" +
+		"Host: " + addr + "\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Key: " + key + "\r\n" +
+		"Sec-WebSocket-Version: 13\r\n\r\n"
+	if _, err := conn.Write([]byte(req)); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, nil)
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		conn.Close()
+		return nil, nil, fmt.Errorf("websocket handshake failed: status %d", resp.StatusCode)
+	}
+	return conn, r, nil
+}
+
+// readTextFrame 读出服务端 wsConn.writeText 写出的一个不分片、无掩码的文本帧。
+func readTextFrame(r *bufio.Reader) ([]byte, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	length := int(header[1] & 0x7F)
+	switch length {
+	case 126:
+		ext := make([]byte, 2)
+		if _, err := io.ReadFull(r, ext); err != nil {
+			return nil, err
+		}
+		length = int(ext[0])<<8 | int(ext[1])
+	case 127:
+		ext := make([]byte, 8)
+		if _, err := io.ReadFull(r, ext); err != nil {
+			return nil, err
+		}
+		length = 0
+		for _, b := range ext {
+			length = length<<8 | int(b)
+		}
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
@@ -0,0 +1,162 @@
+package transport
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"leader_board/leader_board/logic"
+	"leader_board/leader_board/model"
+)
+
+// TopNDelta 描述自上次推送以来 top-N 发生的变化：新进入、跌出，或名次/分数发生了变化。
+type TopNDelta struct {
+	Added   []model.RankInfo `json:"added"`
+	Removed []model.RankInfo `json:"removed"`
+	Moved   []model.RankInfo `json:"moved"`
+}
+
+func (d TopNDelta) empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Moved) == 0
+}
+
+// pushTarget 是一个订阅者的推送出口，WebSocket 连接和 gRPC 流各有一种实现。
+type pushTarget interface {
+	push(delta TopNDelta) error
+}
+
+type subscriber struct {
+	n      int
+	target pushTarget
+
+	// mu 串行化同一个订阅者的推送：OnScoreChange 可能被多个并发的 UpdateScore 调用
+	// 同时触发，若不加锁，并发的 pushTo 会互相踩到彼此对 last 的读写（且可能交错
+	// 生成乱序的增量发给同一个订阅者）。
+	mu   sync.Mutex
+	last map[string]model.RankInfo
+}
+
+// Hub 把 UpdateScore 产生的分数变化，转换成每个订阅者各自 top-N 的增量并推送出去。
+type Hub struct {
+	svc *logic.LeaderboardService
+
+	mu   sync.Mutex
+	subs map[*subscriber]struct{}
+}
+
+// NewHub 基于 svc 创建一个推送中心；需要在每次 UpdateScore 之后调用 OnScoreChange。
+func NewHub(svc *logic.LeaderboardService) *Hub {
+	return &Hub{svc: svc, subs: make(map[*subscriber]struct{})}
+}
+
+func (h *Hub) subscribe(n int, target pushTarget) *subscriber {
+	sub := &subscriber{n: n, target: target, last: make(map[string]model.RankInfo)}
+	h.mu.Lock()
+	h.subs[sub] = struct{}{}
+	h.mu.Unlock()
+	h.pushTo(sub)
+	return sub
+}
+
+func (h *Hub) unsubscribe(sub *subscriber) {
+	h.mu.Lock()
+	delete(h.subs, sub)
+	h.mu.Unlock()
+}
+
+// OnScoreChange 应在每次 UpdateScore 之后调用，向所有订阅者推送各自 top-N 的增量变化。
+func (h *Hub) OnScoreChange() {
+	h.mu.Lock()
+	subs := make([]*subscriber, 0, len(h.subs))
+	for s := range h.subs {
+		subs = append(subs, s)
+	}
+	h.mu.Unlock()
+
+	for _, sub := range subs {
+		h.pushTo(sub)
+	}
+}
+
+func (h *Hub) pushTo(sub *subscriber) {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+
+	top := h.svc.GetTopN(sub.n)
+	current := make(map[string]model.RankInfo, len(top))
+	for _, r := range top {
+		current[r.PlayerID] = r
+	}
+
+	var delta TopNDelta
+	for id, r := range current {
+		prev, existed := sub.last[id]
+		switch {
+		case !existed:
+			delta.Added = append(delta.Added, r)
+		case prev.Rank != r.Rank || prev.Score != r.Score:
+			delta.Moved = append(delta.Moved, r)
+		}
+	}
+	for id, r := range sub.last {
+		if _, stillIn := current[id]; !stillIn {
+			delta.Removed = append(delta.Removed, r)
+		}
+	}
+	sub.last = current
+
+	if delta.empty() {
+		return
+	}
+	if err := sub.target.push(delta); err != nil {
+		h.unsubscribe(sub)
+	}
+}
+
+// wsTarget 把增量编码为 JSON，通过一条 WebSocket 文本帧发出去。
+type wsTarget struct{ conn *wsConn }
+
+func (t wsTarget) push(delta TopNDelta) error {
+	payload, err := json.Marshal(delta)
+	if err != nil {
+		return err
+	}
+	return t.conn.writeText(payload)
+}
+
+// chanTarget 把增量投递进一个带缓冲的 channel，供 gRPC server-streaming 消费；
+// channel 已满时丢弃本次增量而不是阻塞 UpdateScore 的调用方。
+type chanTarget chan TopNDelta
+
+func (t chanTarget) push(delta TopNDelta) error {
+	select {
+	case t <- delta:
+	default:
+	}
+	return nil
+}
+
+// SubscribeChan 为 gRPC SubscribeTopN 一类的流式消费者开一个增量 channel。
+func (h *Hub) SubscribeChan(n int) (*subscriber, <-chan TopNDelta) {
+	ch := make(chanTarget, 16)
+	sub := h.subscribe(n, ch)
+	return sub, ch
+}
+
+// Unsubscribe 注销一个此前订阅的消费者。
+func (h *Hub) Unsubscribe(sub *subscriber) {
+	h.unsubscribe(sub)
+}
+
+// ServeSubscribeTopN 把请求升级为 WebSocket 连接，并持续推送 top-N 增量，
+// 直到客户端断开连接（此时 writeText 返回 error，订阅随之被移除）。
+func (h *Hub) ServeSubscribeTopN(n int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgradeWebSocket(w, r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		h.subscribe(n, wsTarget{conn: conn})
+	}
+}
@@ -0,0 +1,90 @@
+package transport
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+)
+
+// wsGUID 是 RFC 6455 定义的、用于计算 Sec-WebSocket-Accept 的固定 magic string。
+const wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// wsConn 是一个最小化的服务端 WebSocket 连接：只支持发送不分片、不加掩码的文本帧，
+// 这正是 SubscribeTopN 推送 JSON 增量所需要的全部能力。
+type wsConn struct {
+	mu sync.Mutex
+	nc net.Conn
+	rw *bufio.ReadWriter
+}
+
+// upgradeWebSocket 通过 http.Hijacker 接管连接并完成一次 WebSocket 握手。
+func upgradeWebSocket(w http.ResponseWriter, r *http.Request) (*wsConn, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		return nil, fmt.Errorf("transport: missing Sec-WebSocket-Key header")
+	}
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, fmt.Errorf("transport: response writer does not support hijacking")
+	}
+	nc, rw, err := hj.Hijack()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + acceptKey(key) + "\r\n\r\n"
+	if _, err := rw.WriteString(resp); err != nil {
+		nc.Close()
+		return nil, err
+	}
+	if err := rw.Flush(); err != nil {
+		nc.Close()
+		return nil, err
+	}
+	return &wsConn{nc: nc, rw: rw}, nil
+}
+
+func acceptKey(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key + wsGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// writeText 发送一个单帧、无掩码的文本帧（opcode 0x1）。
+func (c *wsConn) writeText(payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := len(payload)
+	var header []byte
+	switch {
+	case n <= 125:
+		header = []byte{0x81, byte(n)}
+	case n <= 0xFFFF:
+		header = []byte{0x81, 126, byte(n >> 8), byte(n)}
+	default:
+		header = []byte{
+			0x81, 127,
+			byte(n >> 56), byte(n >> 48), byte(n >> 40), byte(n >> 32),
+			byte(n >> 24), byte(n >> 16), byte(n >> 8), byte(n),
+		}
+	}
+	if _, err := c.rw.Write(header); err != nil {
+		return err
+	}
+	if _, err := c.rw.Write(payload); err != nil {
+		return err
+	}
+	return c.rw.Flush()
+}
+
+func (c *wsConn) Close() error {
+	return c.nc.Close()
+}